@@ -2,19 +2,36 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
 
+	"invoice-ai-excel/internal/archiveimport"
+	"invoice-ai-excel/internal/filetype"
+	"invoice-ai-excel/internal/openutil"
+	"invoice-ai-excel/internal/recents"
+	"invoice-ai-excel/internal/watcher"
+
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// recentsChangedEvent is emitted to the frontend whenever the recent files
+// list is mutated, so the UI can refresh without polling.
+const recentsChangedEvent = "recents:changed"
+
 type App struct {
-	ctx context.Context
+	ctx      context.Context
+	recents  *recents.Store
+	tempDirs []string
+	watcher  *watcher.Watcher
+}
+
+// WatcherStatus describes one currently watched root, for the WatcherStatus
+// RPC exposed to the frontend.
+type WatcherStatus struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
 }
 
 type FileInfo struct {
@@ -23,11 +40,6 @@ type FileInfo struct {
 	Type string
 }
 
-type FilePathEntry struct {
-	FilePath string `json:"file_path"`
-	Type     string `json:"type"`
-}
-
 // NewApp creates a new App application struct
 func NewApp() *App {
 	return &App{}
@@ -37,112 +49,182 @@ func NewApp() *App {
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
-}
 
-func (a *App) DeleteFileFromJSON(filePath string) (bool, error) {
-	jsonFilePath := "./frontend/src/data/file_path.json"
+	// Each subsystem below is a convenience feature; one failing to
+	// initialize must not block startup or take down the others.
 
-	var existingData []FilePathEntry
+	if store, err := newRecentsStore(); err != nil {
+		wailsRuntime.LogErrorf(ctx, "recents: failed to initialize store: %v", err)
+	} else {
+		a.recents = store
+	}
 
-	if _, err := os.Stat(jsonFilePath); os.IsNotExist(err) {
-		return false, fmt.Errorf("JSON file not found")
+	if w, err := watcher.New(a.emitWatcherEvent); err != nil {
+		wailsRuntime.LogErrorf(ctx, "watcher: failed to initialize: %v", err)
+	} else {
+		a.watcher = w
 	}
+}
 
-	fileData, err := os.ReadFile(jsonFilePath)
-	if err != nil {
-		return false, err
+// shutdown is called when the app is closing. It cleans up any scratch
+// directories created by SelectArchive and stops the file watcher.
+func (a *App) shutdown(ctx context.Context) {
+	for _, dir := range a.tempDirs {
+		os.RemoveAll(dir)
 	}
+	a.tempDirs = nil
 
-	err = json.Unmarshal(fileData, &existingData)
-	if err != nil {
-		return false, err
+	if a.watcher != nil {
+		a.watcher.Close()
 	}
+}
 
-	normalizedTarget := strings.ReplaceAll(strings.TrimSpace(filePath), "\\", "/")
+// emitWatcherEvent forwards a debounced watcher.Event to the frontend as a
+// folder:changed or excel:changed Wails event.
+func (a *App) emitWatcherEvent(ev watcher.Event) {
+	eventName := "folder:changed"
+	if ev.Kind == watcher.ExcelChanged {
+		eventName = "excel:changed"
+	}
+	wailsRuntime.EventsEmit(a.ctx, eventName, ev.Path)
+}
 
-	var updatedData []FilePathEntry
-	found := false
+// StartWatch begins watching path, which may be a folder of invoice images
+// (previously returned by SelectFolder/SelectArchive) or an Excel workbook
+// (previously returned by SelectExcelFile).
+func (a *App) StartWatch(path string) error {
+	if a.watcher == nil {
+		return fmt.Errorf("watcher not initialized")
+	}
 
-	for _, item := range existingData {
-		itemPath := strings.ReplaceAll(strings.TrimSpace(item.FilePath), "\\", "/")
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat watch target: %w", err)
+	}
 
-		if itemPath == normalizedTarget || filepath.Base(itemPath) == filepath.Base(normalizedTarget) {
-			found = true
-			continue
-		}
+	kind := watcher.FolderChanged
+	if !info.IsDir() {
+		kind = watcher.ExcelChanged
+	}
+	return a.watcher.Watch(path, kind)
+}
 
-		updatedData = append(updatedData, item)
+// StopWatch stops watching path.
+func (a *App) StopWatch(path string) error {
+	if a.watcher == nil {
+		return fmt.Errorf("watcher not initialized")
 	}
+	return a.watcher.Unwatch(path)
+}
 
-	if !found {
-		return false, fmt.Errorf("file path not found in JSON")
+// WatcherStatus reports every currently watched folder and Excel file.
+func (a *App) WatcherStatus() []WatcherStatus {
+	if a.watcher == nil {
+		return nil
 	}
 
-	updatedJSON, err := json.MarshalIndent(updatedData, "", "  ")
-	if err != nil {
-		return false, err
+	status := a.watcher.Status()
+	out := make([]WatcherStatus, 0, len(status))
+	for path, kind := range status {
+		out = append(out, WatcherStatus{Path: path, Kind: string(kind)})
 	}
+	return out
+}
 
-	err = os.WriteFile(jsonFilePath, updatedJSON, 0644)
+// newRecentsStore builds the default JSON-backed recents store rooted at
+// the OS user config directory.
+func newRecentsStore() (*recents.Store, error) {
+	path, err := recents.DefaultPath()
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-
-	return true, nil
+	return recents.NewStore(recents.NewJSONBackend(path), recents.DefaultMaxEntries)
 }
 
-func (a *App) AddFileToJSON(filePath string) (bool, error) {
-	jsonFilePath := "./frontend/src/data/file_path.json"
-
-	var existingData []FilePathEntry
-
-	if _, err := os.Stat(jsonFilePath); !os.IsNotExist(err) {
-		fileData, err := os.ReadFile(jsonFilePath)
-		if err != nil {
-			return false, err
-		}
+// emitRecentsChanged notifies the frontend that the recents list changed.
+func (a *App) emitRecentsChanged() {
+	if a.ctx == nil {
+		return
+	}
+	wailsRuntime.EventsEmit(a.ctx, recentsChangedEvent, a.recents.List())
+}
 
-		json.Unmarshal(fileData, &existingData)
+// AddRecent records filePath (of the given type, e.g. "excel" or "image")
+// as the most recently used file of its kind.
+func (a *App) AddRecent(filePath, fileType string) (recents.Entry, error) {
+	if a.recents == nil {
+		return recents.Entry{}, fmt.Errorf("recents store not initialized")
 	}
 
-	normalizedPath := strings.ReplaceAll(filePath, "\\", "/")
-	for _, item := range existingData {
-		if strings.ReplaceAll(item.FilePath, "\\", "/") == normalizedPath {
-			return true, nil
-		}
+	entry, err := a.recents.Add(filePath, fileType)
+	if err != nil {
+		return recents.Entry{}, err
 	}
+	a.emitRecentsChanged()
+	return entry, nil
+}
 
-	newEntry := FilePathEntry{
-		FilePath: normalizedPath,
-		Type:     "excel",
+// RemoveRecent deletes filePath from the recents list, if present.
+func (a *App) RemoveRecent(filePath string) (bool, error) {
+	if a.recents == nil {
+		return false, fmt.Errorf("recents store not initialized")
 	}
 
-	existingData = append([]FilePathEntry{newEntry}, existingData...)
+	removed, err := a.recents.Remove(filePath)
+	if err != nil {
+		return false, err
+	}
+	if removed {
+		a.emitRecentsChanged()
+	}
+	return removed, nil
+}
 
-	if len(existingData) > 50 {
-		existingData = existingData[:50]
+// ListRecents returns the current recent files, most recent first.
+func (a *App) ListRecents() ([]recents.Entry, error) {
+	if a.recents == nil {
+		return nil, fmt.Errorf("recents store not initialized")
 	}
+	return a.recents.List(), nil
+}
 
-	os.MkdirAll(filepath.Dir(jsonFilePath), 0755)
+// ClearRecents removes every unpinned entry from the recents list.
+func (a *App) ClearRecents() error {
+	if a.recents == nil {
+		return fmt.Errorf("recents store not initialized")
+	}
 
-	updatedJSON, err := json.MarshalIndent(existingData, "", "  ")
-	if err != nil {
-		return false, err
+	if err := a.recents.Clear(); err != nil {
+		return err
 	}
+	a.emitRecentsChanged()
+	return nil
+}
 
-	err = os.WriteFile(jsonFilePath, updatedJSON, 0644)
-	if err != nil {
-		return false, err
+// PinRecent pins or unpins filePath so it survives ClearRecents and the
+// maxEntries cap.
+func (a *App) PinRecent(filePath string, pinned bool) error {
+	if a.recents == nil {
+		return fmt.Errorf("recents store not initialized")
 	}
 
-	return true, nil
+	if err := a.recents.Pin(filePath, pinned); err != nil {
+		return err
+	}
+	a.emitRecentsChanged()
+	return nil
 }
 
-func (a *App) SelectFolder() ([]FileInfo, error) {
-	// Open folder selection dialog with smaller size
-	selectedDir, err := wailsRuntime.OpenDirectoryDialog(a.ctx, wailsRuntime.OpenDialogOptions{
-		Title:  "Select Image Folder",
-	})
+// SelectFolder opens a directory picker and returns the image files found
+// directly inside it. Title defaults to "Select Image Folder"; pass
+// Title(...)/DefaultPath(...) to override.
+func (a *App) SelectFolder(options ...DialogOption) ([]FileInfo, error) {
+	o := buildDialogOpts(options)
+	if o.title == "" {
+		o.title = "Select Image Folder"
+	}
+
+	selectedDir, err := wailsRuntime.OpenDirectoryDialog(a.ctx, o.openOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -182,38 +264,83 @@ func (a *App) SelectFolder() ([]FileInfo, error) {
 	return files, nil
 }
 
-func (a *App) ValidateImgFiles(files []FileInfo) []FileInfo {
-	var validFiles []FileInfo
-	for _, file := range files {
-		ext := strings.ToLower(filepath.Ext(file.Path))
-		// Only validate image files here
+// SelectArchive lets the user pick a .zip, .tar, .tar.gz, or .tar.bz2 bundle
+// of invoice images, extracts it into a scratch directory, and returns the
+// same FileInfo shape SelectFolder produces for loose folders.
+func (a *App) SelectArchive() ([]FileInfo, error) {
+	o := dialogOpts{
+		title: "Select Invoice Archive",
+		filters: []FileFilter{
+			{Name: "Archives (*.zip, *.tar, *.tar.gz, *.tar.bz2)", Exts: []string{"zip", "tar", "tar.gz", "tar.bz2", "tgz", "tbz2"}},
+		},
+	}
+	selectedArchive, err := wailsRuntime.OpenFileDialog(a.ctx, o.openOptions())
+	if err != nil {
+		return nil, err
+	}
+	if selectedArchive == "" {
+		return []FileInfo{}, nil
+	}
+
+	destDir, err := os.MkdirTemp("", "invoice-ai-excel-archive-*")
+	if err != nil {
+		return nil, fmt.Errorf("create extraction dir: %w", err)
+	}
+	a.tempDirs = append(a.tempDirs, destDir)
+
+	extracted, err := archiveimport.Extract(selectedArchive, destDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileInfo
+	for _, ef := range extracted {
+		ext := strings.ToLower(filepath.Ext(ef.Name))
 		if ext == ".jpg" || ext == ".jpeg" || ext == ".png" || ext == ".gif" {
-			validFiles = append(validFiles, FileInfo{
-				Name: file.Name,
-				Path: file.Path,
+			files = append(files, FileInfo{
+				Name: ef.Name,
+				Path: ef.Path,
 				Type: "image",
 			})
 		}
 	}
-	return validFiles
+
+	return files, nil
 }
 
-// Add new function for Excel file selection
-func (a *App) SelectExcelFile() (*FileInfo, error) {
-	selectedFile, err := wailsRuntime.OpenFileDialog(a.ctx, wailsRuntime.OpenDialogOptions{
-		Title: "Select Excel File",
-		Filters: []wailsRuntime.FileFilter{
-			{
-				DisplayName: "Excel Files (*.xlsx, *.xls)",
-				Pattern:     "*.xlsx;*.xls",
-			},
-		},
-	})
+// ValidateImgFiles sniffs each file's content (rather than trusting its
+// extension) and reports a per-file ValidationResult so the UI can surface
+// why a file was rejected instead of silently dropping it.
+func (a *App) ValidateImgFiles(files []FileInfo) []filetype.ValidationResult {
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.Path
+	}
+	return filetype.ValidateImages(paths)
+}
+
+// defaultExcelFilters is the Filters(...) value used by SelectExcelFile and
+// SaveExcelAs unless the caller supplies their own.
+var defaultExcelFilters = []FileFilter{
+	{Name: "Excel Files (*.xlsx, *.xls)", Exts: []string{"xlsx", "xls"}},
+}
+
+// SelectExcelFile opens a file picker for an existing workbook. Title
+// defaults to "Select Excel File" and Filters defaults to
+// defaultExcelFilters unless overridden.
+func (a *App) SelectExcelFile(options ...DialogOption) (*FileInfo, error) {
+	o := buildDialogOpts(options)
+	if o.title == "" {
+		o.title = "Select Excel File"
+	}
+	if len(o.filters) == 0 {
+		o.filters = defaultExcelFilters
+	}
 
+	selectedFile, err := wailsRuntime.OpenFileDialog(a.ctx, o.openOptions())
 	if err != nil {
 		return nil, err
 	}
-
 	if selectedFile == "" {
 		return nil, nil
 	}
@@ -225,87 +352,128 @@ func (a *App) SelectExcelFile() (*FileInfo, error) {
 	}, nil
 }
 
-func (a *App) ValidateExcelFile(file *FileInfo) bool {
-	if file == nil {
-		return false
+// SelectImages opens a file picker for loose invoice images. Pass
+// Multiple() to allow selecting more than one file at once.
+func (a *App) SelectImages(options ...DialogOption) ([]FileInfo, error) {
+	o := buildDialogOpts(options)
+	if o.title == "" {
+		o.title = "Select Invoice Images"
+	}
+	if len(o.filters) == 0 {
+		o.filters = []FileFilter{{Name: "Images (*.jpg, *.jpeg, *.png, *.gif)", Exts: []string{"jpg", "jpeg", "png", "gif"}}}
 	}
-	ext := strings.ToLower(filepath.Ext(file.Path))
-	return ext == ".xlsx" || ext == ".xls"
-}
 
-// NEW: Open file in default application
-func (a *App) OpenFile(filePath string) error {
-	// Clean and validate the file path
-	cleanPath := filepath.Clean(filePath)
+	var selected []string
+	if o.multiple {
+		paths, err := wailsRuntime.OpenMultipleFilesDialog(a.ctx, o.openOptions())
+		if err != nil {
+			return nil, err
+		}
+		selected = paths
+	} else {
+		path, err := wailsRuntime.OpenFileDialog(a.ctx, o.openOptions())
+		if err != nil {
+			return nil, err
+		}
+		if path != "" {
+			selected = []string{path}
+		}
+	}
 
-	// Check if file exists
-	if _, err := os.Stat(cleanPath); os.IsNotExist(err) {
-		return fmt.Errorf("file does not exist: %s", cleanPath)
+	files := make([]FileInfo, 0, len(selected))
+	for _, path := range selected {
+		files = append(files, FileInfo{
+			Name: filepath.Base(path),
+			Path: path,
+			Type: "image",
+		})
 	}
+	return files, nil
+}
 
-	var cmd *exec.Cmd
+// SaveExcelAs prompts for an output workbook path. Filename defaults to
+// "invoices.xlsx" and Filters defaults to defaultExcelFilters unless
+// overridden. With ConfirmOverwrite(), the user is asked before an
+// existing file at the chosen path is replaced.
+func (a *App) SaveExcelAs(options ...DialogOption) (string, error) {
+	o := buildDialogOpts(options)
+	if o.title == "" {
+		o.title = "Save Excel Workbook As"
+	}
+	if o.filename == "" {
+		o.filename = "invoices.xlsx"
+	}
+	if len(o.filters) == 0 {
+		o.filters = defaultExcelFilters
+	}
 
-	switch runtime.GOOS {
-	case "windows":
-		// Use 'start' command on Windows
-		cmd = exec.Command("cmd", "/c", "start", "", cleanPath)
-	case "darwin":
-		// Use 'open' command on macOS
-		cmd = exec.Command("open", cleanPath)
-	case "linux":
-		// Use 'xdg-open' command on Linux
-		cmd = exec.Command("xdg-open", cleanPath)
-	default:
-		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	path, err := wailsRuntime.SaveFileDialog(a.ctx, o.saveOptions())
+	if err != nil || path == "" {
+		return path, err
 	}
 
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to open file: %v", err)
+	if o.confirmOverwrite {
+		if _, statErr := os.Stat(path); statErr == nil {
+			result, dialogErr := wailsRuntime.MessageDialog(a.ctx, wailsRuntime.MessageDialogOptions{
+				Type:    wailsRuntime.QuestionDialog,
+				Title:   "Overwrite file?",
+				Message: fmt.Sprintf("%s already exists. Overwrite it?", filepath.Base(path)),
+				Buttons: []string{"Overwrite", "Cancel"},
+			})
+			if dialogErr != nil {
+				return "", dialogErr
+			}
+			if result != "Overwrite" {
+				return "", nil
+			}
+		}
 	}
 
-	return nil
+	return path, nil
 }
 
-// NEW: Open folder in file explorer
-func (a *App) OpenFolder(folderPath string) error {
-	// Clean and validate the folder path
-	cleanPath := filepath.Clean(folderPath)
-
-	// Check if folder exists
-	if _, err := os.Stat(cleanPath); os.IsNotExist(err) {
-		return fmt.Errorf("folder does not exist: %s", cleanPath)
-	}
-
-	var cmd *exec.Cmd
-
-	switch runtime.GOOS {
-	case "windows":
-		// Use 'explorer' command on Windows
-		cmd = exec.Command("explorer", cleanPath)
-	case "darwin":
-		// Use 'open' command on macOS
-		cmd = exec.Command("open", cleanPath)
-	case "linux":
-		// Use file manager on Linux (try multiple options)
-		fileManagers := []string{"nautilus", "dolphin", "thunar", "pcmanfm", "caja"}
-		for _, fm := range fileManagers {
-			if _, err := exec.LookPath(fm); err == nil {
-				cmd = exec.Command(fm, cleanPath)
-				break
-			}
-		}
-		if cmd == nil {
-			return fmt.Errorf("no suitable file manager found on Linux")
-		}
-	default:
-		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+// ValidateExcelFile sniffs file's content to confirm it's a genuine .xlsx
+// (zip container with [Content_Types].xml) or legacy .xls (CFBF) workbook.
+func (a *App) ValidateExcelFile(file *FileInfo) filetype.ValidationResult {
+	if file == nil {
+		return filetype.ValidationResult{Ok: false, Reason: "no file selected"}
 	}
+	return filetype.ValidateExcel([]string{file.Path})[0]
+}
 
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to open folder: %v", err)
+// ValidateFiles sniffs a mixed batch of files, dispatching each to the
+// image or Excel validator based on its Type, so the UI can show per-file
+// error reasons rather than a silent filter.
+func (a *App) ValidateFiles(files []FileInfo) []filetype.ValidationResult {
+	results := make([]filetype.ValidationResult, 0, len(files))
+	for _, file := range files {
+		if file.Type == "excel" {
+			results = append(results, filetype.ValidateExcel([]string{file.Path})[0])
+			continue
+		}
+		results = append(results, filetype.ValidateImages([]string{file.Path})[0])
 	}
+	return results
+}
 
-	return nil
+// OpenFile opens filePath in the OS default application. Implementation is
+// platform-specific (see internal/openutil) and never shells out through
+// cmd.exe/sh, so paths containing &, ^, or quotes can't be parsed as
+// additional commands.
+func (a *App) OpenFile(filePath string) error {
+	return openutil.OpenFile(filePath)
+}
+
+// OpenFolder opens folderPath in the platform's file manager.
+func (a *App) OpenFolder(folderPath string) error {
+	return openutil.OpenFolder(folderPath)
+}
+
+// RevealInFolder opens path's parent folder with path selected/highlighted,
+// where the platform supports it (Explorer, Finder, or a D-Bus-aware Linux
+// file manager).
+func (a *App) RevealInFolder(path string) error {
+	return openutil.RevealInFolder(path)
 }
 
 // NEW: Get file size in human readable format
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// FileFilter describes one named group of file extensions for a dialog.
+// Exts are bare extensions without the leading dot (e.g. "xlsx", "png");
+// they're translated to the "*.ext;*.ext" pattern syntax Wails expects.
+type FileFilter struct {
+	Name string
+	Exts []string
+}
+
+// dialogOpts is the options struct DialogOption mutates. The dialog methods
+// that accept options build it with buildDialogOpts; SelectArchive, which
+// takes no options, constructs one directly.
+type dialogOpts struct {
+	title            string
+	defaultPath      string
+	filename         string
+	confirmOverwrite bool
+	multiple         bool
+	filters          []FileFilter
+}
+
+// DialogOption configures a file/folder dialog opened via SelectFolder,
+// SelectImages, SelectExcelFile, or SaveExcelAs.
+type DialogOption func(*dialogOpts)
+
+// Title sets the dialog window title.
+func Title(title string) DialogOption {
+	return func(o *dialogOpts) { o.title = title }
+}
+
+// DefaultPath sets the directory the dialog opens in.
+func DefaultPath(path string) DialogOption {
+	return func(o *dialogOpts) { o.defaultPath = path }
+}
+
+// Filename pre-fills the suggested filename (SaveExcelAs only).
+func Filename(name string) DialogOption {
+	return func(o *dialogOpts) { o.filename = name }
+}
+
+// ConfirmOverwrite asks the user before replacing an existing file
+// (SaveExcelAs only).
+func ConfirmOverwrite() DialogOption {
+	return func(o *dialogOpts) { o.confirmOverwrite = true }
+}
+
+// Multiple allows selecting more than one file (SelectImages only).
+func Multiple() DialogOption {
+	return func(o *dialogOpts) { o.multiple = true }
+}
+
+// Filters sets the named extension groups offered by the dialog.
+func Filters(filters ...FileFilter) DialogOption {
+	return func(o *dialogOpts) { o.filters = filters }
+}
+
+// buildDialogOpts applies options in order over a zero-value dialogOpts.
+func buildDialogOpts(options []DialogOption) dialogOpts {
+	var o dialogOpts
+	for _, opt := range options {
+		opt(&o)
+	}
+	return o
+}
+
+// wailsFilters translates the FileFilter group into Wails' pattern syntax.
+func (o dialogOpts) wailsFilters() []wailsRuntime.FileFilter {
+	out := make([]wailsRuntime.FileFilter, 0, len(o.filters))
+	for _, f := range o.filters {
+		patterns := make([]string, len(f.Exts))
+		for i, ext := range f.Exts {
+			patterns[i] = "*." + strings.TrimPrefix(ext, ".")
+		}
+		out = append(out, wailsRuntime.FileFilter{
+			DisplayName: f.Name,
+			Pattern:     strings.Join(patterns, ";"),
+		})
+	}
+	return out
+}
+
+// openOptions builds the Wails options for an Open(Directory/File)Dialog call.
+func (o dialogOpts) openOptions() wailsRuntime.OpenDialogOptions {
+	return wailsRuntime.OpenDialogOptions{
+		Title:            o.title,
+		DefaultDirectory: o.defaultPath,
+		Filters:          o.wailsFilters(),
+	}
+}
+
+// saveOptions builds the Wails options for a SaveFileDialog call.
+func (o dialogOpts) saveOptions() wailsRuntime.SaveDialogOptions {
+	return wailsRuntime.SaveDialogOptions{
+		Title:                o.title,
+		DefaultDirectory:     o.defaultPath,
+		DefaultFilename:      o.filename,
+		Filters:              o.wailsFilters(),
+		CanCreateDirectories: true,
+	}
+}
@@ -0,0 +1,215 @@
+// Package archiveimport extracts a zip/tar/tar.gz/tar.bz2 bundle of invoice
+// images into a scratch directory so the rest of the app can treat it like
+// any other folder the user picked with SelectFolder.
+package archiveimport
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxUncompressedBytes bounds the total size written to disk while
+// extracting a single archive, guarding against zip-bomb style attacks.
+const MaxUncompressedBytes = 1 << 30 // 1 GiB
+
+// MaxEntries bounds the number of files an archive may contain.
+const MaxEntries = 5000
+
+// ExtractedFile describes a file pulled out of the archive.
+type ExtractedFile struct {
+	Name string
+	Path string
+}
+
+// budget tracks how much an in-progress extraction has consumed against
+// MaxUncompressedBytes / MaxEntries.
+type budget struct {
+	bytesLeft   int64
+	entriesLeft int
+}
+
+func newBudget() *budget {
+	return &budget{bytesLeft: MaxUncompressedBytes, entriesLeft: MaxEntries}
+}
+
+func (b *budget) spendEntry() error {
+	if b.entriesLeft <= 0 {
+		return fmt.Errorf("archive has too many entries (limit %d)", MaxEntries)
+	}
+	b.entriesLeft--
+	return nil
+}
+
+func (b *budget) spendBytes(n int64) error {
+	b.bytesLeft -= n
+	if b.bytesLeft < 0 {
+		return fmt.Errorf("archive exceeds uncompressed size limit (%d bytes)", MaxUncompressedBytes)
+	}
+	return nil
+}
+
+// Extract dispatches on archivePath's (lowercased) extension and extracts
+// its contents into destDir, which must already exist. It returns the
+// extracted files in archive order.
+func Extract(archivePath, destDir string) ([]ExtractedFile, error) {
+	switch archiveKind(archivePath) {
+	case ".zip":
+		return extractZip(archivePath, destDir)
+	case ".tar":
+		f, err := os.Open(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("open archive: %w", err)
+		}
+		defer f.Close()
+		return extractTar(f, destDir)
+	case ".tar.gz":
+		f, err := os.Open(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("open archive: %w", err)
+		}
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("open gzip stream: %w", err)
+		}
+		defer gz.Close()
+		return extractTar(gz, destDir)
+	case ".tar.bz2":
+		f, err := os.Open(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("open archive: %w", err)
+		}
+		defer f.Close()
+		return extractTar(bzip2.NewReader(f), destDir)
+	default:
+		return nil, fmt.Errorf("unsupported archive type: %s", archivePath)
+	}
+}
+
+// archiveKind returns the lowercased, multi-dot-aware extension used to
+// route extraction (mirrors the .tar.gz/.tar.bz2 special-casing common in
+// Go archive-handling examples).
+func archiveKind(path string) string {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return ".tar.gz"
+	case strings.HasSuffix(lower, ".tar.bz2"), strings.HasSuffix(lower, ".tbz2"):
+		return ".tar.bz2"
+	default:
+		return filepath.Ext(lower)
+	}
+}
+
+func extractZip(archivePath, destDir string) ([]ExtractedFile, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("open zip: %w", err)
+	}
+	defer r.Close()
+
+	b := newBudget()
+	var out []ExtractedFile
+
+	for _, zf := range r.File {
+		if zf.FileInfo().IsDir() {
+			continue
+		}
+		if err := b.spendEntry(); err != nil {
+			return nil, err
+		}
+
+		destPath, err := safeJoin(destDir, zf.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open zip entry %s: %w", zf.Name, err)
+		}
+		err = writeEntry(destPath, rc, b)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, ExtractedFile{Name: filepath.Base(destPath), Path: destPath})
+	}
+
+	return out, nil
+}
+
+func extractTar(r io.Reader, destDir string) ([]ExtractedFile, error) {
+	tr := tar.NewReader(r)
+	b := newBudget()
+	var out []ExtractedFile
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read tar entry: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := b.spendEntry(); err != nil {
+			return nil, err
+		}
+
+		destPath, err := safeJoin(destDir, hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeEntry(destPath, tr, b); err != nil {
+			return nil, err
+		}
+
+		out = append(out, ExtractedFile{Name: filepath.Base(destPath), Path: destPath})
+	}
+
+	return out, nil
+}
+
+// safeJoin joins destDir with the archive-supplied name, rejecting any
+// entry whose cleaned path would escape destDir (the "zip-slip" attack).
+func safeJoin(destDir, name string) (string, error) {
+	cleaned := filepath.Clean(filepath.Join(destDir, name))
+	if cleaned != destDir && !strings.HasPrefix(cleaned, destDir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry escapes extraction root: %s", name)
+	}
+	return cleaned, nil
+}
+
+// writeEntry copies r into destPath, enforcing b's remaining byte budget as
+// it goes rather than trusting the archive's declared size up front.
+func writeEntry(destPath string, r io.Reader, b *budget) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("create extraction dir: %w", err)
+	}
+
+	out, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("create extracted file %s: %w", destPath, err)
+	}
+	defer out.Close()
+
+	limited := io.LimitReader(r, b.bytesLeft+1)
+	written, err := io.Copy(out, limited)
+	if err != nil {
+		return fmt.Errorf("write extracted file %s: %w", destPath, err)
+	}
+
+	return b.spendBytes(written)
+}
@@ -0,0 +1,128 @@
+// Package filetype sniffs file contents to classify invoice images and
+// Excel workbooks, instead of trusting a renamed or corrupt file's
+// extension the way naive filepath.Ext checks do.
+package filetype
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// sniffLen is the number of leading bytes inspected, matching the amount
+// net/http.DetectContentType looks at.
+const sniffLen = 512
+
+// xlsSignature is the CFBF (OLE2) magic number used by the legacy .xls format.
+var xlsSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// zipSignature is the local file header magic number shared by .zip and,
+// by extension, .xlsx (which is a zip container).
+var zipSignature = []byte("PK\x03\x04")
+
+// ValidationResult is the outcome of sniffing a single file.
+type ValidationResult struct {
+	Path         string
+	DetectedMIME string
+	Ok           bool
+	Reason       string
+}
+
+func sniffHeader(path string) ([]byte, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffLen)
+	n, err := f.Read(buf)
+	if err != nil && err != io.EOF {
+		return nil, "", fmt.Errorf("read %s: %w", path, err)
+	}
+	buf = buf[:n]
+
+	return buf, http.DetectContentType(buf), nil
+}
+
+// ValidateImages sniffs each path and reports whether it's a PNG, JPEG, or
+// GIF image.
+func ValidateImages(paths []string) []ValidationResult {
+	results := make([]ValidationResult, 0, len(paths))
+	for _, path := range paths {
+		_, mime, err := sniffHeader(path)
+		if err != nil {
+			results = append(results, ValidationResult{Path: path, Ok: false, Reason: err.Error()})
+			continue
+		}
+
+		ok := strings.HasPrefix(mime, "image/png") ||
+			strings.HasPrefix(mime, "image/jpeg") ||
+			strings.HasPrefix(mime, "image/gif")
+
+		result := ValidationResult{Path: path, DetectedMIME: mime, Ok: ok}
+		if !ok {
+			result.Reason = fmt.Sprintf("not a recognized image (detected %s)", mime)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// ValidateExcel sniffs each path and reports whether it's a legacy .xls
+// (CFBF) or modern .xlsx (zip container with [Content_Types].xml) workbook.
+func ValidateExcel(paths []string) []ValidationResult {
+	results := make([]ValidationResult, 0, len(paths))
+	for _, path := range paths {
+		header, mime, err := sniffHeader(path)
+		if err != nil {
+			results = append(results, ValidationResult{Path: path, Ok: false, Reason: err.Error()})
+			continue
+		}
+
+		switch {
+		case bytes.HasPrefix(header, xlsSignature):
+			results = append(results, ValidationResult{
+				Path:         path,
+				DetectedMIME: "application/vnd.ms-excel",
+				Ok:           true,
+			})
+		case bytes.HasPrefix(header, zipSignature) && hasXLSXContentTypes(path):
+			results = append(results, ValidationResult{
+				Path:         path,
+				DetectedMIME: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+				Ok:           true,
+			})
+		default:
+			results = append(results, ValidationResult{
+				Path:         path,
+				DetectedMIME: mime,
+				Ok:           false,
+				Reason:       fmt.Sprintf("not a recognized Excel workbook (detected %s)", mime),
+			})
+		}
+	}
+	return results
+}
+
+// hasXLSXContentTypes confirms the zip container at path carries the
+// [Content_Types].xml member every OOXML package must have, distinguishing
+// a genuine .xlsx from an arbitrary renamed .zip.
+func hasXLSXContentTypes(path string) bool {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return false
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name == "[Content_Types].xml" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+// Package openutil opens files and folders in the user's default
+// application/file manager without ever going through a shell, so paths
+// containing characters like &, ^, or quotes can't be interpreted as
+// additional commands.
+package openutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// OpenFile opens path in the OS default application for its type.
+func OpenFile(path string) error {
+	clean, err := preparePath(path)
+	if err != nil {
+		return err
+	}
+	return openFile(clean)
+}
+
+// OpenFolder opens the folder at path in the platform's file manager.
+func OpenFolder(path string) error {
+	clean, err := preparePath(path)
+	if err != nil {
+		return err
+	}
+	return openFolder(clean)
+}
+
+// RevealInFolder opens path's parent folder with path selected, where the
+// platform supports it, falling back to just opening the parent folder.
+func RevealInFolder(path string) error {
+	clean, err := preparePath(path)
+	if err != nil {
+		return err
+	}
+	return reveal(clean)
+}
+
+// preparePath cleans path and confirms it exists before any command is
+// built from it.
+func preparePath(path string) (string, error) {
+	clean := filepath.Clean(path)
+	if _, err := os.Stat(clean); err != nil {
+		return "", fmt.Errorf("path does not exist: %s", clean)
+	}
+	return clean, nil
+}
+
+// run starts name with args and returns once the process has launched.
+// Arguments are passed directly to the child process (no shell is
+// involved), so argument-injection characters in a path are never parsed.
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("launch %s: %w", name, err)
+	}
+	return nil
+}
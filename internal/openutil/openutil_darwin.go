@@ -0,0 +1,20 @@
+//go:build darwin
+
+package openutil
+
+// openFile opens path in its default application via the macOS `open`
+// command.
+func openFile(path string) error {
+	return run("open", path)
+}
+
+// openFolder opens path (a directory) in Finder.
+func openFolder(path string) error {
+	return run("open", path)
+}
+
+// reveal opens path's parent folder in Finder with path selected, via
+// `open -R`.
+func reveal(path string) error {
+	return run("open", "-R", path)
+}
@@ -0,0 +1,109 @@
+//go:build linux
+
+package openutil
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// fileManagers is tried in order when no xdg-open-style opener is available.
+var fileManagers = []string{"nautilus", "dolphin", "thunar", "pcmanfm", "caja"}
+
+func openFile(path string) error {
+	if cmd, args, ok := resolveOpener(exec.LookPath); ok {
+		return run(cmd, append(args, path)...)
+	}
+	return fmt.Errorf("no suitable opener found for %s", path)
+}
+
+func openFolder(path string) error {
+	if cmd, args, ok := resolveOpener(exec.LookPath); ok {
+		return run(cmd, append(args, path)...)
+	}
+	for _, fm := range fileManagers {
+		if _, err := exec.LookPath(fm); err == nil {
+			return run(fm, path)
+		}
+	}
+	return fmt.Errorf("no suitable file manager found on Linux")
+}
+
+// resolveOpener picks the best available opener for the current
+// environment: flatpak-spawn when sandboxed, wslview/explorer.exe under
+// WSL, then plain xdg-open. lookPath is injected so tests can stub it
+// without depending on what's actually installed.
+func resolveOpener(lookPath func(string) (string, error)) (cmd string, args []string, ok bool) {
+	if isFlatpak() {
+		if _, err := lookPath("flatpak-spawn"); err == nil {
+			return "flatpak-spawn", []string{"--host", "xdg-open"}, true
+		}
+	}
+
+	if isWSL() {
+		if _, err := lookPath("wslview"); err == nil {
+			return "wslview", nil, true
+		}
+		if _, err := lookPath("explorer.exe"); err == nil {
+			return "explorer.exe", nil, true
+		}
+	}
+
+	if _, err := lookPath("xdg-open"); err == nil {
+		return "xdg-open", nil, true
+	}
+
+	return "", nil, false
+}
+
+// isFlatpak reports whether the process is running inside a Flatpak
+// sandbox, where direct xdg-open calls are blocked.
+func isFlatpak() bool {
+	_, err := os.Stat("/.flatpak-info")
+	return err == nil
+}
+
+// isWSL reports whether the kernel is a WSL build, where xdg-open has no
+// desktop session to talk to.
+func isWSL() bool {
+	data, err := os.ReadFile("/proc/version")
+	if err != nil {
+		return false
+	}
+	lower := strings.ToLower(string(data))
+	return strings.Contains(lower, "microsoft") || strings.Contains(lower, "wsl")
+}
+
+// reveal asks the user's file manager to highlight path over D-Bus
+// (org.freedesktop.FileManager1.ShowItems), falling back to just opening
+// its parent folder if no listener answers.
+func reveal(path string) error {
+	if revealViaDBus(path) {
+		return nil
+	}
+	return openFolder(filepath.Dir(path))
+}
+
+// revealViaDBus asks the user's file manager to highlight path over D-Bus
+// and reports whether the call actually succeeded. It runs synchronously
+// (unlike the fire-and-forget run() helper) so a non-zero exit — e.g. no
+// process owns org.freedesktop.FileManager1 — is observed before reveal
+// decides whether to fall back.
+func revealViaDBus(path string) bool {
+	fileURI := (&url.URL{Scheme: "file", Path: path}).String()
+	args := []string{
+		"--session",
+		"--print-reply",
+		"--dest=org.freedesktop.FileManager1",
+		"--type=method_call",
+		"/org/freedesktop/FileManager1",
+		"org.freedesktop.FileManager1.ShowItems",
+		fmt.Sprintf("array:string:%s", fileURI),
+		"string:",
+	}
+	return exec.Command("dbus-send", args...).Run() == nil
+}
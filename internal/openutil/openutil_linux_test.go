@@ -0,0 +1,38 @@
+//go:build linux
+
+package openutil
+
+import "testing"
+
+func TestResolveOpenerPrefersXdgOpen(t *testing.T) {
+	lookPath := func(name string) (string, error) {
+		if name == "xdg-open" {
+			return "/usr/bin/xdg-open", nil
+		}
+		return "", errNotFound
+	}
+
+	cmd, args, ok := resolveOpener(lookPath)
+	if !ok {
+		t.Fatalf("resolveOpener() ok = false, want true")
+	}
+	if cmd != "xdg-open" || len(args) != 0 {
+		t.Errorf("resolveOpener() = (%q, %v), want (%q, [])", cmd, args, "xdg-open")
+	}
+}
+
+func TestResolveOpenerReportsNoOpener(t *testing.T) {
+	lookPath := func(name string) (string, error) {
+		return "", errNotFound
+	}
+
+	if _, _, ok := resolveOpener(lookPath); ok {
+		t.Errorf("resolveOpener() ok = true, want false when nothing is installed")
+	}
+}
+
+var errNotFound = &lookPathError{}
+
+type lookPathError struct{}
+
+func (*lookPathError) Error() string { return "not found" }
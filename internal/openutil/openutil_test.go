@@ -0,0 +1,45 @@
+package openutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPreparePathAcceptsTrickyNames(t *testing.T) {
+	dir := t.TempDir()
+
+	names := []string{
+		"plain.png",
+		"has spaces.png",
+		"has&ampersand.png",
+		"ünïcödé-名前.png",
+		"trailing.dot.",
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+				t.Fatalf("write test file: %v", err)
+			}
+
+			clean, err := preparePath(path)
+			if err != nil {
+				t.Fatalf("preparePath(%q): %v", path, err)
+			}
+			if clean != filepath.Clean(path) {
+				t.Errorf("preparePath(%q) = %q, want %q", path, clean, filepath.Clean(path))
+			}
+		})
+	}
+}
+
+func TestPreparePathRejectsMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "does-not-exist.png")
+
+	if _, err := preparePath(missing); err == nil {
+		t.Fatalf("preparePath(%q) = nil error, want error for missing file", missing)
+	}
+}
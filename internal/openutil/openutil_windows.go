@@ -0,0 +1,61 @@
+//go:build windows
+
+package openutil
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	shell32           = windows.NewLazySystemDLL("shell32.dll")
+	procShellExecuteW = shell32.NewProc("ShellExecuteW")
+)
+
+// openFile opens path in its default application via ShellExecuteW. This
+// bypasses cmd.exe entirely (unlike `cmd /c start`), so path is never
+// parsed as a command line and characters like &, ^, or quotes can't be
+// used to inject additional commands.
+func openFile(path string) error {
+	return shellExecute("open", path)
+}
+
+// openFolder opens path (a directory) in Explorer via ShellExecuteW.
+func openFolder(path string) error {
+	return shellExecute("open", path)
+}
+
+// reveal opens path's parent folder in Explorer with path selected, via
+// `explorer /select,<path>`.
+func reveal(path string) error {
+	return run("explorer", "/select,"+path)
+}
+
+// shellExecute invokes shell32's ShellExecuteW with the given verb and
+// path, avoiding cmd.exe's command-line parsing altogether.
+func shellExecute(verb, path string) error {
+	verbPtr, err := windows.UTF16PtrFromString(verb)
+	if err != nil {
+		return fmt.Errorf("encode verb: %w", err)
+	}
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return fmt.Errorf("encode path: %w", err)
+	}
+
+	ret, _, _ := procShellExecuteW.Call(
+		0,
+		uintptr(unsafe.Pointer(verbPtr)),
+		uintptr(unsafe.Pointer(pathPtr)),
+		0,
+		0,
+		uintptr(windows.SW_SHOWNORMAL),
+	)
+	// Per the Win32 docs, ShellExecute returns a value > 32 on success.
+	if ret <= 32 {
+		return fmt.Errorf("ShellExecute failed with code %d", ret)
+	}
+	return nil
+}
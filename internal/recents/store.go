@@ -0,0 +1,296 @@
+// Package recents implements a small persisted "recent files" list shared by
+// the Excel workbook and invoice image folder pickers. Entries are stored as
+// JSON under the OS user config directory by default, but the on-disk format
+// is abstracted behind the Backend interface so it can be swapped for
+// something like BoltDB or SQLite without touching callers.
+package recents
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CurrentSchemaVersion is bumped whenever the on-disk shape of Snapshot
+// changes. migrate() upgrades an older Snapshot in memory when it is loaded;
+// the file itself is rewritten in the new shape on the next Save.
+const CurrentSchemaVersion = 1
+
+// DefaultMaxEntries is used when callers don't override the cap via NewStore.
+const DefaultMaxEntries = 50
+
+// Entry describes a single recently used file.
+type Entry struct {
+	Path    string    `json:"path"`
+	Type    string    `json:"type"`
+	Pinned  bool      `json:"pinned"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// Snapshot is the full contents of the backing store.
+type Snapshot struct {
+	SchemaVersion int     `json:"schema_version"`
+	Entries       []Entry `json:"entries"`
+}
+
+// Backend persists and loads a Snapshot. Snapshot is exported so a
+// BoltDB/SQLite backend defined in another package can implement this
+// interface too; JSONBackend is just the default implementation.
+type Backend interface {
+	Load() (*Snapshot, error)
+	Save(*Snapshot) error
+}
+
+// JSONBackend stores the recents list as a single JSON file, written
+// atomically (temp file + rename) so a crash mid-write can't corrupt it.
+type JSONBackend struct {
+	path string
+}
+
+// NewJSONBackend returns a Backend backed by the JSON file at path.
+func NewJSONBackend(path string) *JSONBackend {
+	return &JSONBackend{path: path}
+}
+
+// DefaultPath returns the recommended location for the recents store:
+// <UserConfigDir>/invoice-ai-excel/recents.json.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user config dir: %w", err)
+	}
+	return filepath.Join(dir, "invoice-ai-excel", "recents.json"), nil
+}
+
+func (b *JSONBackend) Load() (*Snapshot, error) {
+	raw, err := os.ReadFile(b.path)
+	if os.IsNotExist(err) {
+		return &Snapshot{SchemaVersion: CurrentSchemaVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read recents store: %w", err)
+	}
+
+	var data Snapshot
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("parse recents store: %w", err)
+	}
+	return &data, nil
+}
+
+func (b *JSONBackend) Save(data *Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return fmt.Errorf("create recents store dir: %w", err)
+	}
+
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode recents store: %w", err)
+	}
+
+	tmpPath := b.path + ".tmp"
+	if err := os.WriteFile(tmpPath, encoded, 0644); err != nil {
+		return fmt.Errorf("write recents store temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, b.path); err != nil {
+		return fmt.Errorf("replace recents store: %w", err)
+	}
+
+	return nil
+}
+
+// Store is a concurrency-safe, deduplicated recent-files list. All mutating
+// methods persist through the configured Backend before returning.
+type Store struct {
+	mu         sync.Mutex
+	backend    Backend
+	maxEntries int
+	data       *Snapshot
+}
+
+// NewStore loads (and migrates, if necessary) the store behind backend.
+// maxEntries caps the number of retained non-pinned entries; if <= 0,
+// DefaultMaxEntries is used.
+func NewStore(backend Backend, maxEntries int) (*Store, error) {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+
+	data, err := backend.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(data); err != nil {
+		return nil, fmt.Errorf("migrate recents store: %w", err)
+	}
+
+	return &Store{backend: backend, maxEntries: maxEntries, data: data}, nil
+}
+
+// migrate upgrades data in place to CurrentSchemaVersion.
+func migrate(data *Snapshot) error {
+	switch data.SchemaVersion {
+	case 0:
+		// Initial shape predates schema_version; nothing to transform beyond
+		// stamping the version.
+		data.SchemaVersion = 1
+		fallthrough
+	case CurrentSchemaVersion:
+		return nil
+	default:
+		return fmt.Errorf("unsupported recents schema_version %d", data.SchemaVersion)
+	}
+}
+
+// canonicalize resolves path to an absolute, symlink-free, cleaned form so
+// the same file can't appear twice under different spellings. If the path
+// doesn't exist yet (or symlinks can't be resolved), it falls back to the
+// cleaned absolute path.
+func canonicalize(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve absolute path: %w", err)
+	}
+
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+
+	return filepath.Clean(abs), nil
+}
+
+// Add inserts path (canonicalized) at the front of the list, moving it to
+// the front if already present. Pinned entries are exempt from the
+// maxEntries cap.
+func (s *Store) Add(path, fileType string) (Entry, error) {
+	canonical, err := canonicalize(path)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pinned := false
+	filtered := s.data.Entries[:0:0]
+	for _, e := range s.data.Entries {
+		if e.Path == canonical {
+			pinned = e.Pinned
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+
+	entry := Entry{Path: canonical, Type: fileType, Pinned: pinned, AddedAt: time.Now()}
+	s.data.Entries = append([]Entry{entry}, filtered...)
+	s.enforceCapLocked()
+
+	if err := s.backend.Save(s.data); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// Remove deletes the entry for path, if present, and reports whether it was found.
+func (s *Store) Remove(path string) (bool, error) {
+	canonical, err := canonicalize(path)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := false
+	filtered := s.data.Entries[:0:0]
+	for _, e := range s.data.Entries {
+		if e.Path == canonical {
+			found = true
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	if !found {
+		return false, nil
+	}
+
+	s.data.Entries = filtered
+	if err := s.backend.Save(s.data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// List returns a copy of the current recents, most recent first.
+func (s *Store) List() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Entry, len(s.data.Entries))
+	copy(out, s.data.Entries)
+	return out
+}
+
+// Clear removes every non-pinned entry.
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var kept []Entry
+	for _, e := range s.data.Entries {
+		if e.Pinned {
+			kept = append(kept, e)
+		}
+	}
+	s.data.Entries = kept
+
+	return s.backend.Save(s.data)
+}
+
+// Pin sets the pinned flag for path, exempting/re-including it from the
+// maxEntries cap accordingly.
+func (s *Store) Pin(path string, pinned bool) error {
+	canonical, err := canonicalize(path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := false
+	for i := range s.data.Entries {
+		if s.data.Entries[i].Path == canonical {
+			s.data.Entries[i].Pinned = pinned
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("recents: path not found: %s", canonical)
+	}
+
+	return s.backend.Save(s.data)
+}
+
+// enforceCapLocked drops the oldest unpinned entries once the unpinned count
+// exceeds maxEntries. Callers must hold s.mu.
+func (s *Store) enforceCapLocked() {
+	kept := make([]Entry, 0, len(s.data.Entries))
+	unpinned := 0
+	for _, e := range s.data.Entries {
+		if !e.Pinned {
+			unpinned++
+			if unpinned > s.maxEntries {
+				continue
+			}
+		}
+		kept = append(kept, e)
+	}
+	s.data.Entries = kept
+}
@@ -0,0 +1,220 @@
+// Package watcher wraps fsnotify to watch invoice image folders and the
+// active Excel workbook, debouncing bursts of Create/Write events on the
+// same path into a single callback so the frontend can refresh without
+// polling.
+package watcher
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DebounceWindow suppresses repeated notifications for the same path
+// within this threshold.
+const DebounceWindow = 400 * time.Millisecond
+
+// Kind distinguishes the two things this package watches.
+type Kind string
+
+const (
+	FolderChanged Kind = "folder"
+	ExcelChanged  Kind = "excel"
+)
+
+// Event is delivered once per debounced change to a watched root.
+type Event struct {
+	Kind Kind
+	Path string
+}
+
+// Watcher fans fsnotify events for one or more watched roots out to a
+// single callback, filtered by file type and debounced per path.
+type Watcher struct {
+	fsw *fsnotify.Watcher
+
+	mu       sync.Mutex
+	watched  map[string]Kind
+	lastSeen map[string]time.Time
+
+	onEvent func(Event)
+	done    chan struct{}
+}
+
+// New starts a Watcher that invokes onEvent for every debounced, filtered
+// change on a watched root.
+func New(onEvent func(Event)) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create fsnotify watcher: %w", err)
+	}
+
+	w := &Watcher{
+		fsw:      fsw,
+		watched:  make(map[string]Kind),
+		lastSeen: make(map[string]time.Time),
+		onEvent:  onEvent,
+		done:     make(chan struct{}),
+	}
+	go w.loop()
+	return w, nil
+}
+
+func (w *Watcher) loop() {
+	for {
+		select {
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handle(ev)
+		case _, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *Watcher) handle(ev fsnotify.Event) {
+	if ev.Op&(fsnotify.Create|fsnotify.Write) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	w.pruneLastSeenLocked(now)
+
+	if last, ok := w.lastSeen[ev.Name]; ok && now.Sub(last) < DebounceWindow {
+		w.lastSeen[ev.Name] = now
+		return
+	}
+	w.lastSeen[ev.Name] = now
+
+	root, kind, ok := w.rootForLocked(ev.Name)
+	if !ok {
+		return
+	}
+	if kind == FolderChanged && !isImagePath(ev.Name) {
+		return
+	}
+	if kind == ExcelChanged && !isExcelPath(ev.Name) {
+		return
+	}
+
+	if w.onEvent != nil {
+		w.onEvent(Event{Kind: kind, Path: root})
+	}
+}
+
+// pruneLastSeenLocked evicts lastSeen entries that have already aged past
+// DebounceWindow, so a long-running watch of an active folder doesn't
+// accumulate one entry per distinct path forever. Callers must hold w.mu.
+func (w *Watcher) pruneLastSeenLocked(now time.Time) {
+	for path, seenAt := range w.lastSeen {
+		if now.Sub(seenAt) >= DebounceWindow {
+			delete(w.lastSeen, path)
+		}
+	}
+}
+
+// rootForLocked maps a raw fsnotify path back to the watched root that
+// produced it: a folder watch reports events for files inside it, while an
+// Excel watch reports events for the file itself. Callers must hold w.mu.
+func (w *Watcher) rootForLocked(path string) (string, Kind, bool) {
+	if kind, ok := w.watched[path]; ok && kind == ExcelChanged {
+		return path, ExcelChanged, true
+	}
+	dir := filepath.Dir(path)
+	if kind, ok := w.watched[dir]; ok && kind == FolderChanged {
+		return dir, FolderChanged, true
+	}
+	return "", "", false
+}
+
+// Watch starts watching path (a folder of invoice images or an Excel
+// workbook file). It is a no-op if path is already watched.
+func (w *Watcher) Watch(path string, kind Kind) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.watched[path]; ok {
+		return nil
+	}
+	if err := w.fsw.Add(path); err != nil {
+		return fmt.Errorf("watch %s: %w", path, err)
+	}
+	w.watched[path] = kind
+	return nil
+}
+
+// Unwatch stops watching path. It is a no-op if path isn't watched.
+func (w *Watcher) Unwatch(path string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	kind, ok := w.watched[path]
+	if !ok {
+		return nil
+	}
+	delete(w.watched, path)
+	if err := w.fsw.Remove(path); err != nil {
+		return fmt.Errorf("unwatch %s: %w", path, err)
+	}
+
+	if kind == ExcelChanged {
+		delete(w.lastSeen, path)
+	} else {
+		for seenPath := range w.lastSeen {
+			if filepath.Dir(seenPath) == path {
+				delete(w.lastSeen, seenPath)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Status returns a snapshot of every currently watched root and its kind.
+func (w *Watcher) Status() map[string]Kind {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out := make(map[string]Kind, len(w.watched))
+	for path, kind := range w.watched {
+		out[path] = kind
+	}
+	return out
+}
+
+// Close stops the watcher and releases its underlying fsnotify resources.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.fsw.Close()
+}
+
+func isImagePath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg", ".png", ".gif":
+		return true
+	default:
+		return false
+	}
+}
+
+func isExcelPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx", ".xls":
+		return true
+	default:
+		return false
+	}
+}